@@ -0,0 +1,233 @@
+package bufio
+
+// Bit-level reading and writing on top of the byte-oriented Reader/Writer.
+//
+// Both a big-endian (MSB-first, as used by MPEG/H.264-style headers) and a
+// little-endian (LSB-first, as used by deflate/bzip2-style streams) bit
+// ordering are supported. Each ordering keeps its own small accumulator
+// (a uint64 holding the valid bits plus a count of how many are valid) so
+// the two orderings can't corrupt each other, though mixing them on the
+// same Reader/Writer without an AlignByte in between makes little sense.
+//
+// Reads/writes wider than 32 bits are split into two <=32-bit chunks so the
+// accumulator, which is refilled/flushed a whole byte at a time, never has
+// to hold more than 39 valid bits at once.
+
+// ReadBits reads the next n (1 <= n <= 64) bits, MSB first, and returns them
+// right-justified in the result.
+func (reader *Reader) ReadBits(n uint) (uint64, error) {
+	if reader.stickyErr != nil {
+		return 0, reader.stickyErr
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	if n > 64 {
+		return 0, ErrBitCountOutOfRange
+	}
+	if n > 32 {
+		hi, err := reader.readBitsBE(32)
+		if err != nil {
+			return 0, err
+		}
+		lo, err := reader.readBitsBE(n - 32)
+		if err != nil {
+			return 0, err
+		}
+		return hi<<(n-32) | lo, nil
+	}
+	return reader.readBitsBE(n)
+}
+
+// readBitsBE reads up to 32 bits, MSB first.
+func (reader *Reader) readBitsBE(n uint) (uint64, error) {
+	for reader.bitCnt < n {
+		// Read through Pop, not ReadByte: ReadByte bypasses the internal
+		// buffer whenever the underlying io.Reader also implements
+		// io.ByteReader, which would desync this accumulator from bytes
+		// already sitting in buf after a prior fixed-width Read*.
+		b, err := reader.Pop(1)
+		if err != nil {
+			return 0, err
+		}
+		reader.bitBuf |= uint64(b[0]) << (56 - reader.bitCnt)
+		reader.bitCnt += 8
+	}
+	v := reader.bitBuf >> (64 - n)
+	reader.bitBuf <<= n
+	reader.bitCnt -= n
+	return v, nil
+}
+
+// ReadBitsSigned reads the next n (1 <= n <= 64) bits, MSB first, and
+// sign-extends them as a two's complement value.
+func (reader *Reader) ReadBitsSigned(n uint) (int64, error) {
+	v, err := reader.ReadBits(n)
+	if err != nil {
+		return 0, err
+	}
+	if n < 64 && v&(1<<(n-1)) != 0 {
+		v |= ^uint64(0) << n
+	}
+	return int64(v), nil
+}
+
+// ReadBitsLE reads the next n (1 <= n <= 64) bits, LSB first, and returns
+// them right-justified in the result.
+func (reader *Reader) ReadBitsLE(n uint) (uint64, error) {
+	if reader.stickyErr != nil {
+		return 0, reader.stickyErr
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	if n > 64 {
+		return 0, ErrBitCountOutOfRange
+	}
+	if n > 32 {
+		lo, err := reader.readBitsLE(32)
+		if err != nil {
+			return 0, err
+		}
+		hi, err := reader.readBitsLE(n - 32)
+		if err != nil {
+			return 0, err
+		}
+		return hi<<32 | lo, nil
+	}
+	return reader.readBitsLE(n)
+}
+
+// readBitsLE reads up to 32 bits, LSB first.
+func (reader *Reader) readBitsLE(n uint) (uint64, error) {
+	for reader.bitCntLE < n {
+		// See readBitsBE: Pop, not ReadByte, to stay in sync with buf.
+		b, err := reader.Pop(1)
+		if err != nil {
+			return 0, err
+		}
+		reader.bitBufLE |= uint64(b[0]) << reader.bitCntLE
+		reader.bitCntLE += 8
+	}
+	var mask uint64 = 1<<n - 1
+	v := reader.bitBufLE & mask
+	reader.bitBufLE >>= n
+	reader.bitCntLE -= n
+	return v, nil
+}
+
+// ReadBitsSignedLE reads the next n (1 <= n <= 64) bits, LSB first, and
+// sign-extends them as a two's complement value.
+func (reader *Reader) ReadBitsSignedLE(n uint) (int64, error) {
+	v, err := reader.ReadBitsLE(n)
+	if err != nil {
+		return 0, err
+	}
+	if n < 64 && v&(1<<(n-1)) != 0 {
+		v |= ^uint64(0) << n
+	}
+	return int64(v), nil
+}
+
+// AlignByte discards any unread bits buffered by ReadBits/ReadBitsLE,
+// advancing to the next byte boundary.
+func (reader *Reader) AlignByte() {
+	reader.bitBuf, reader.bitCnt = 0, 0
+	reader.bitBufLE, reader.bitCntLE = 0, 0
+}
+
+// WriteBits writes the low n (1 <= n <= 64) bits of v, MSB first, buffering
+// any partial byte until it is completed or flushed by AlignByte.
+func (writer *Writer) WriteBits(v uint64, n uint) error {
+	if writer.err != nil {
+		return writer.err
+	}
+	if n == 0 {
+		return nil
+	}
+	if n > 64 {
+		return ErrBitCountOutOfRange
+	}
+	if n > 32 {
+		if err := writer.writeBitsBE(v>>(n-32), 32); err != nil {
+			return err
+		}
+		return writer.writeBitsBE(v, n-32)
+	}
+	return writer.writeBitsBE(v, n)
+}
+
+// writeBitsBE writes up to 32 bits, MSB first.
+func (writer *Writer) writeBitsBE(v uint64, n uint) error {
+	if n < 64 {
+		v &= 1<<n - 1
+	}
+	writer.bitBuf |= v << (64 - writer.bitCnt - n)
+	writer.bitCnt += n
+	for writer.bitCnt >= 8 {
+		if _, err := writer.WriteUint8(byte(writer.bitBuf >> 56)); err != nil {
+			return err
+		}
+		writer.bitBuf <<= 8
+		writer.bitCnt -= 8
+	}
+	return nil
+}
+
+// WriteBitsLE writes the low n (1 <= n <= 64) bits of v, LSB first,
+// buffering any partial byte until it is completed or flushed by
+// AlignByte.
+func (writer *Writer) WriteBitsLE(v uint64, n uint) error {
+	if writer.err != nil {
+		return writer.err
+	}
+	if n == 0 {
+		return nil
+	}
+	if n > 64 {
+		return ErrBitCountOutOfRange
+	}
+	if n > 32 {
+		if err := writer.writeBitsLE(v, 32); err != nil {
+			return err
+		}
+		return writer.writeBitsLE(v>>32, n-32)
+	}
+	return writer.writeBitsLE(v, n)
+}
+
+// writeBitsLE writes up to 32 bits, LSB first.
+func (writer *Writer) writeBitsLE(v uint64, n uint) error {
+	if n < 64 {
+		v &= 1<<n - 1
+	}
+	writer.bitBufLE |= v << writer.bitCntLE
+	writer.bitCntLE += n
+	for writer.bitCntLE >= 8 {
+		if _, err := writer.WriteUint8(byte(writer.bitBufLE)); err != nil {
+			return err
+		}
+		writer.bitBufLE >>= 8
+		writer.bitCntLE -= 8
+	}
+	return nil
+}
+
+// AlignByte pads any partially written bits from WriteBits/WriteBitsLE with
+// zeroes, flushes the resulting byte(s), and advances to the next byte
+// boundary.
+func (writer *Writer) AlignByte() error {
+	if writer.bitCnt > 0 {
+		if _, err := writer.WriteUint8(byte(writer.bitBuf >> 56)); err != nil {
+			return err
+		}
+		writer.bitBuf, writer.bitCnt = 0, 0
+	}
+	if writer.bitCntLE > 0 {
+		if _, err := writer.WriteUint8(byte(writer.bitBufLE)); err != nil {
+			return err
+		}
+		writer.bitBufLE, writer.bitCntLE = 0, 0
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package bufio
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReadBitsRoundTrip(t *testing.T) {
+	cases := []struct {
+		name string
+		n    uint
+	}{
+		{"1 bit", 1},
+		{"32 bits", 32},
+		{"33 bits", 33},
+		{"64 bits", 64},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := NewWriter(&buf)
+			want := uint64(1)<<(tc.n-1) | 1
+			if err := w.WriteBits(want, tc.n); err != nil {
+				t.Fatalf("WriteBits: %v", err)
+			}
+			if err := w.AlignByte(); err != nil {
+				t.Fatalf("AlignByte: %v", err)
+			}
+			if err := w.Flush(); err != nil {
+				t.Fatalf("Flush: %v", err)
+			}
+
+			r := NewReader(&buf)
+			got, err := r.ReadBits(tc.n)
+			if err != nil {
+				t.Fatalf("ReadBits: %v", err)
+			}
+			if got != want {
+				t.Fatalf("ReadBits(%d) = %#x, want %#x", tc.n, got, want)
+			}
+		})
+	}
+}
+
+func TestReadBitsLERoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	want := uint64(0xDEADBEEF)
+	if err := w.WriteBitsLE(want, 33); err != nil {
+		t.Fatalf("WriteBitsLE: %v", err)
+	}
+	if err := w.AlignByte(); err != nil {
+		t.Fatalf("AlignByte: %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	r := NewReader(&buf)
+	got, err := r.ReadBitsLE(33)
+	if err != nil {
+		t.Fatalf("ReadBitsLE: %v", err)
+	}
+	if got != want {
+		t.Fatalf("ReadBitsLE(33) = %#x, want %#x", got, want)
+	}
+}
+
+// TestReadBitsAfterFixedWidthRead is a regression test for ReadByte
+// bypassing the internal buffer on a reader that also implements
+// io.ByteReader (e.g. *strings.Reader): a prior ReadUint16BE must not
+// cause the following ReadBits to read past the buffered data.
+func TestReadBitsAfterFixedWidthRead(t *testing.T) {
+	r := NewReader(strings.NewReader("\x12\x34\xAB\xCD"))
+
+	v16, err := r.ReadUint16BE()
+	if err != nil {
+		t.Fatalf("ReadUint16BE: %v", err)
+	}
+	if v16 != 0x1234 {
+		t.Fatalf("ReadUint16BE() = %#x, want 0x1234", v16)
+	}
+
+	bits, err := r.ReadBits(8)
+	if err != nil {
+		t.Fatalf("ReadBits(8): %v", err)
+	}
+	if bits != 0xAB {
+		t.Fatalf("ReadBits(8) = %#x, want 0xAB", bits)
+	}
+}
@@ -21,6 +21,8 @@ var (
 	ErrInvalidUnreadRune = errors.New("bufio: invalid use of UnreadRune")
 	ErrBufferFull        = errors.New("bufio: buffer full")
 	ErrNegativeCount     = errors.New("bufio: negative count")
+
+	ErrBitCountOutOfRange = errors.New("bufio: bit count out of range [1, 64]")
 )
 
 // Buffered input.
@@ -31,6 +33,18 @@ type Reader struct {
 	rd   io.Reader // reader provided by the client
 	r, w int       // buf read and write positions
 	err  error
+
+	bitBuf   uint64 // MSB-first bit accumulator, left-justified
+	bitCnt   uint   // number of valid bits in bitBuf
+	bitBufLE uint64 // LSB-first bit accumulator, right-justified
+	bitCntLE uint   // number of valid bits in bitBufLE
+
+	maxFramedLen    int  // max length accepted by ReadBytesU*/ReadStringU*, see SetMaxFramedLen
+	maxFramedLenSet bool // whether SetMaxFramedLen has been called
+
+	stickyErr error // sticky error for the fluent Read* API, see Err/ClearErr
+
+	sect *sectionReader // non-nil if this Reader was created by Section
 }
 
 const minReadBufferSize = 16
@@ -149,14 +163,31 @@ func (b *Reader) Peek(n int) ([]byte, error) {
 // also returns an error explaining why the read is short. The error is
 // ErrBufferFull if n is larger than b's buffer size.
 func (b *Reader) Pop(n int) ([]byte, error) {
+	if b.stickyErr != nil {
+		return nil, b.stickyErr
+	}
 	if d, err := b.Peek(n); err == nil {
 		b.r += n
 		return d, err
 	} else {
+		b.stickyErr = err
 		return nil, err
 	}
 }
 
+// Err returns the sticky error, if any, accumulated by a prior failed
+// Read* call. Once set, every Read* method becomes a no-op that returns
+// this same error, until ClearErr is called.
+func (b *Reader) Err() error {
+	return b.stickyErr
+}
+
+// ClearErr resets the sticky error set by a prior failed Read* call,
+// allowing Read* calls to reach the underlying reader again.
+func (b *Reader) ClearErr() {
+	b.stickyErr = nil
+}
+
 // Discard skips the next n bytes, returning the number of bytes discarded.
 //
 // If Discard skips fewer than n bytes, it also returns an error.
@@ -196,6 +227,14 @@ func (b *Reader) Discard(n int) (discarded int, err error) {
 // hence n may be less than len(p).
 // At EOF, the count will be zero and err will be io.EOF.
 func (b *Reader) Read(p []byte) (n int, err error) {
+	if b.stickyErr != nil {
+		return 0, b.stickyErr
+	}
+	defer func() {
+		if err != nil {
+			b.stickyErr = err
+		}
+	}()
 	n = len(p)
 	if n == 0 {
 		return 0, b.readErr()
@@ -234,9 +273,19 @@ func (b *Reader) Buffered() int { return b.w - b.r }
 
 // =================================
 func (reader *Reader) ReadBytes(n int) (b []byte, err error) {
+	if reader.stickyErr != nil {
+		return nil, reader.stickyErr
+	}
 	b = make([]byte, n)
-	_, reader.err = io.ReadFull(reader.rd, b)
-	return b, reader.err
+	// Read through reader itself, not reader.rd directly, so any bytes
+	// already sitting in the internal buffer (from a prior Pop/Peek) are
+	// consumed before falling through to the underlying io.Reader.
+	_, err = io.ReadFull(reader, b)
+	reader.err = err
+	if err != nil {
+		reader.stickyErr = err
+	}
+	return b, err
 }
 
 func (reader *Reader) ReadString(n int) (string, error) {
@@ -259,8 +308,15 @@ func (reader *Reader) ReadString(n int) (string, error) {
 //}
 
 func (reader *Reader) ReadByte() (byte, error) {
+	if reader.stickyErr != nil {
+		return 0, reader.stickyErr
+	}
 	if byteReader, ok := reader.rd.(io.ByteReader); ok {
-		return byteReader.ReadByte()
+		b, err := byteReader.ReadByte()
+		if err != nil {
+			reader.stickyErr = err
+		}
+		return b, err
 	}
 	if b, err := reader.Pop(1); err == nil {
 		return byte(b[0]), nil
@@ -429,6 +485,11 @@ type Writer struct {
 	buf []byte
 	n   int
 	wr  io.Writer
+
+	bitBuf   uint64 // MSB-first bit accumulator, left-justified
+	bitCnt   uint   // number of valid bits in bitBuf
+	bitBufLE uint64 // LSB-first bit accumulator, right-justified
+	bitCntLE uint   // number of valid bits in bitBufLE
 }
 
 // NewWriterSize returns a new Writer whose buffer has at least the specified
@@ -477,6 +538,19 @@ func (b *Writer) Flush() error {
 	return err
 }
 
+// Err returns the sticky error, if any, that has put the Writer into an
+// error state. Once set, every Write* method becomes a no-op that returns
+// this same error, until ClearErr is called.
+func (b *Writer) Err() error {
+	return b.err
+}
+
+// ClearErr resets the sticky error set by a prior failed Write* call,
+// allowing Write* calls to reach the underlying writer again.
+func (b *Writer) ClearErr() {
+	b.err = nil
+}
+
 func (b *Writer) flush() error {
 	if b.err != nil {
 		return b.err
@@ -0,0 +1,57 @@
+package bufio
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestReaderStickyErrorNoOpUntilCleared(t *testing.T) {
+	reader := NewReader(bytes.NewReader([]byte{0x01}))
+
+	if _, err := reader.Pop(2); err != io.EOF {
+		t.Fatalf("Pop(2) past EOF: err = %v, want io.EOF", err)
+	}
+	if err := reader.Err(); err != io.EOF {
+		t.Fatalf("Err() = %v, want io.EOF", err)
+	}
+
+	// Every Read* call is now a no-op that returns the same sticky error,
+	// even though the reader's own buffer still has data it hasn't
+	// reported.
+	if v, err := reader.ReadUint8(); err != io.EOF || v != 0 {
+		t.Fatalf("ReadUint8() = (%v, %v), want (0, io.EOF)", v, err)
+	}
+
+	reader.ClearErr()
+	if err := reader.Err(); err != nil {
+		t.Fatalf("Err() after ClearErr() = %v, want nil", err)
+	}
+}
+
+func TestWriterStickyErrorNoOpUntilCleared(t *testing.T) {
+	writer := NewWriterSize(failingWriter{}, 16)
+
+	if _, err := writer.WriteUint8(1); err != nil {
+		t.Fatalf("WriteUint8() buffering: %v", err)
+	}
+	if err := writer.Flush(); err == nil {
+		t.Fatal("Flush() into a failing writer: err = nil, want non-nil")
+	}
+	if writer.Err() == nil {
+		t.Fatal("Err() after failed Flush = nil, want non-nil")
+	}
+
+	if _, err := writer.WriteUint8(2); err == nil {
+		t.Fatal("WriteUint8() after sticky error: err = nil, want the same sticky error")
+	}
+
+	writer.ClearErr()
+	if writer.Err() != nil {
+		t.Fatalf("Err() after ClearErr() = %v, want nil", writer.Err())
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, io.ErrClosedPipe }
@@ -0,0 +1,243 @@
+package bufio
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrFrameTooLarge is returned by the ReadBytesU*/ReadStringU* family when a
+// decoded length prefix exceeds the configured maximum, guarding against
+// unbounded allocations driven by hostile input.
+var ErrFrameTooLarge = errors.New("bufio: framed length exceeds max")
+
+// ErrLengthPrefixOverflow is returned by the WriteBytesU*/WriteStringU*
+// family when len(b) does not fit in the helper's length-prefix width,
+// which would otherwise silently narrow (and corrupt) the prefix.
+var ErrLengthPrefixOverflow = errors.New("bufio: length does not fit in prefix width")
+
+func checkWriteFramedLen(n int, max uint64) error {
+	if uint64(n) > max {
+		return ErrLengthPrefixOverflow
+	}
+	return nil
+}
+
+// defaultMaxFramedLen is used until SetMaxFramedLen is called.
+const defaultMaxFramedLen = 1 << 20 // 1 MiB
+
+// SetMaxFramedLen sets the maximum length accepted by the length-prefixed
+// ReadBytesU*/ReadStringU* helpers. n <= 0 disables the check.
+func (reader *Reader) SetMaxFramedLen(n int) {
+	reader.maxFramedLen = n
+	reader.maxFramedLenSet = true
+}
+
+func (reader *Reader) checkFramedLen(n int) error {
+	max := defaultMaxFramedLen
+	if reader.maxFramedLenSet {
+		max = reader.maxFramedLen
+	}
+	if max > 0 && n > max {
+		return ErrFrameTooLarge
+	}
+	return nil
+}
+
+func (reader *Reader) readFramed(n int) ([]byte, error) {
+	if err := reader.checkFramedLen(n); err != nil {
+		return nil, err
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(reader, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// ReadBytesU8 reads a uint8 length prefix followed by that many bytes.
+func (reader *Reader) ReadBytesU8() ([]byte, error) {
+	n, err := reader.ReadUint8()
+	if err != nil {
+		return nil, err
+	}
+	return reader.readFramed(int(n))
+}
+
+// ReadBytesU16BE reads a big-endian uint16 length prefix followed by that
+// many bytes.
+func (reader *Reader) ReadBytesU16BE() ([]byte, error) {
+	n, err := reader.ReadUint16BE()
+	if err != nil {
+		return nil, err
+	}
+	return reader.readFramed(int(n))
+}
+
+// ReadBytesU16LE reads a little-endian uint16 length prefix followed by
+// that many bytes.
+func (reader *Reader) ReadBytesU16LE() ([]byte, error) {
+	n, err := reader.ReadUint16LE()
+	if err != nil {
+		return nil, err
+	}
+	return reader.readFramed(int(n))
+}
+
+// ReadBytesU32BE reads a big-endian uint32 length prefix followed by that
+// many bytes.
+func (reader *Reader) ReadBytesU32BE() ([]byte, error) {
+	n, err := reader.ReadUint32BE()
+	if err != nil {
+		return nil, err
+	}
+	return reader.readFramed(int(n))
+}
+
+// ReadBytesU32LE reads a little-endian uint32 length prefix followed by
+// that many bytes.
+func (reader *Reader) ReadBytesU32LE() ([]byte, error) {
+	n, err := reader.ReadUint32LE()
+	if err != nil {
+		return nil, err
+	}
+	return reader.readFramed(int(n))
+}
+
+// ReadStringU8 reads a uint8 length prefix followed by that many bytes,
+// returned as a string.
+func (reader *Reader) ReadStringU8() (string, error) {
+	b, err := reader.ReadBytesU8()
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// ReadStringU16BE reads a big-endian uint16 length prefix followed by that
+// many bytes, returned as a string.
+func (reader *Reader) ReadStringU16BE() (string, error) {
+	b, err := reader.ReadBytesU16BE()
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// ReadStringU16LE reads a little-endian uint16 length prefix followed by
+// that many bytes, returned as a string.
+func (reader *Reader) ReadStringU16LE() (string, error) {
+	b, err := reader.ReadBytesU16LE()
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// ReadStringU32BE reads a big-endian uint32 length prefix followed by that
+// many bytes, returned as a string.
+func (reader *Reader) ReadStringU32BE() (string, error) {
+	b, err := reader.ReadBytesU32BE()
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// ReadStringU32LE reads a little-endian uint32 length prefix followed by
+// that many bytes, returned as a string.
+func (reader *Reader) ReadStringU32LE() (string, error) {
+	b, err := reader.ReadBytesU32LE()
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// WriteBytesU8 writes a uint8 length prefix followed by b. It returns
+// ErrLengthPrefixOverflow if len(b) does not fit in a uint8.
+func (writer *Writer) WriteBytesU8(b []byte) (int, error) {
+	if err := checkWriteFramedLen(len(b), 1<<8-1); err != nil {
+		return 0, err
+	}
+	if _, err := writer.WriteUint8(uint8(len(b))); err != nil {
+		return 0, err
+	}
+	return writer.WriteBytes(b)
+}
+
+// WriteBytesU16BE writes a big-endian uint16 length prefix followed by b.
+// It returns ErrLengthPrefixOverflow if len(b) does not fit in a uint16.
+func (writer *Writer) WriteBytesU16BE(b []byte) (int, error) {
+	if err := checkWriteFramedLen(len(b), 1<<16-1); err != nil {
+		return 0, err
+	}
+	if _, err := writer.WriteUint16BE(uint16(len(b))); err != nil {
+		return 0, err
+	}
+	return writer.WriteBytes(b)
+}
+
+// WriteBytesU16LE writes a little-endian uint16 length prefix followed by
+// b. It returns ErrLengthPrefixOverflow if len(b) does not fit in a
+// uint16.
+func (writer *Writer) WriteBytesU16LE(b []byte) (int, error) {
+	if err := checkWriteFramedLen(len(b), 1<<16-1); err != nil {
+		return 0, err
+	}
+	if _, err := writer.WriteUint16LE(uint16(len(b))); err != nil {
+		return 0, err
+	}
+	return writer.WriteBytes(b)
+}
+
+// WriteBytesU32BE writes a big-endian uint32 length prefix followed by b.
+// It returns ErrLengthPrefixOverflow if len(b) does not fit in a uint32.
+func (writer *Writer) WriteBytesU32BE(b []byte) (int, error) {
+	if err := checkWriteFramedLen(len(b), 1<<32-1); err != nil {
+		return 0, err
+	}
+	if _, err := writer.WriteUint32BE(uint32(len(b))); err != nil {
+		return 0, err
+	}
+	return writer.WriteBytes(b)
+}
+
+// WriteBytesU32LE writes a little-endian uint32 length prefix followed by
+// b. It returns ErrLengthPrefixOverflow if len(b) does not fit in a
+// uint32.
+func (writer *Writer) WriteBytesU32LE(b []byte) (int, error) {
+	if err := checkWriteFramedLen(len(b), 1<<32-1); err != nil {
+		return 0, err
+	}
+	if _, err := writer.WriteUint32LE(uint32(len(b))); err != nil {
+		return 0, err
+	}
+	return writer.WriteBytes(b)
+}
+
+// WriteStringU8 writes a uint8 length prefix followed by s.
+func (writer *Writer) WriteStringU8(s string) (int, error) {
+	return writer.WriteBytesU8([]byte(s))
+}
+
+// WriteStringU16BE writes a big-endian uint16 length prefix followed by s.
+func (writer *Writer) WriteStringU16BE(s string) (int, error) {
+	return writer.WriteBytesU16BE([]byte(s))
+}
+
+// WriteStringU16LE writes a little-endian uint16 length prefix followed by
+// s.
+func (writer *Writer) WriteStringU16LE(s string) (int, error) {
+	return writer.WriteBytesU16LE([]byte(s))
+}
+
+// WriteStringU32BE writes a big-endian uint32 length prefix followed by s.
+func (writer *Writer) WriteStringU32BE(s string) (int, error) {
+	return writer.WriteBytesU32BE([]byte(s))
+}
+
+// WriteStringU32LE writes a little-endian uint32 length prefix followed by
+// s.
+func (writer *Writer) WriteStringU32LE(s string) (int, error) {
+	return writer.WriteBytesU32LE([]byte(s))
+}
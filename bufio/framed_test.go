@@ -0,0 +1,40 @@
+package bufio
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSetMaxFramedLenZeroDisablesCheck(t *testing.T) {
+	big := bytes.Repeat([]byte{0}, 2<<20) // 2 MiB, over the 1 MiB default
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	if _, err := w.WriteBytesU32BE(big); err != nil {
+		t.Fatalf("WriteBytesU32BE: %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	r := NewReader(&buf)
+	r.SetMaxFramedLen(0)
+	got, err := r.ReadBytesU32BE()
+	if err != nil {
+		t.Fatalf("ReadBytesU32BE() with disabled check: %v", err)
+	}
+	if len(got) != len(big) {
+		t.Fatalf("ReadBytesU32BE() len = %d, want %d", len(got), len(big))
+	}
+}
+
+func TestWriteBytesU8OverflowRejected(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	oversized := bytes.Repeat([]byte{0xFF}, 300)
+	if _, err := w.WriteBytesU8(oversized); err != ErrLengthPrefixOverflow {
+		t.Fatalf("WriteBytesU8() err = %v, want ErrLengthPrefixOverflow", err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("WriteBytesU8() wrote %d bytes on rejected input, want 0", buf.Len())
+	}
+}
@@ -0,0 +1,59 @@
+package bufio
+
+import "io"
+
+// sectionReader is the io.Reader backing a Reader returned by Section: it
+// hands out at most `remaining` more bytes from parent before reporting
+// io.EOF, regardless of how much more parent actually has.
+type sectionReader struct {
+	parent    *Reader
+	remaining int64
+}
+
+func (s *sectionReader) Read(p []byte) (int, error) {
+	if s.remaining <= 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > s.remaining {
+		p = p[:s.remaining]
+	}
+	n, err := s.parent.Read(p)
+	s.remaining -= int64(n)
+	return n, err
+}
+
+// Section returns a new *Reader limited to the next n bytes of reader, for
+// parsing a length-prefixed sub-record (TLV / chunked formats) as if it
+// were its own independent stream. Bytes are pulled from the parent
+// lazily, as the child is read, rather than eagerly up front.
+//
+// Reads past the n-byte boundary return io.EOF. The child must either be
+// fully consumed or explicitly Close()'d; Close discards whatever part of
+// the section the child never read, so the parent ends up positioned
+// exactly after the section regardless of how much of it the caller
+// actually consumed.
+func (reader *Reader) Section(n int) (*Reader, error) {
+	if n < 0 {
+		return nil, ErrNegativeCount
+	}
+	sect := &sectionReader{parent: reader, remaining: int64(n)}
+	child := NewReader(sect)
+	child.sect = sect
+	return child, nil
+}
+
+// Close discards any bytes of the section that were never read by this
+// Reader, advancing the parent Reader (as returned by Section) past the
+// section. It is a no-op on a Reader not created by Section.
+func (reader *Reader) Close() error {
+	if reader.sect == nil {
+		return nil
+	}
+	sect := reader.sect
+	if sect.remaining <= 0 {
+		return nil
+	}
+	_, err := sect.parent.Discard(int(sect.remaining))
+	sect.remaining = 0
+	return err
+}
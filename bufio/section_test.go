@@ -0,0 +1,54 @@
+package bufio
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestSectionEOFsAtBoundary(t *testing.T) {
+	parent := NewReader(bytes.NewReader([]byte("hello world")))
+
+	child, err := parent.Section(5)
+	if err != nil {
+		t.Fatalf("Section: %v", err)
+	}
+
+	got, err := child.ReadBytes(5)
+	if err != nil {
+		t.Fatalf("ReadBytes(5) within section: %v", err)
+	}
+	if string(got) != "hello" {
+		t.Fatalf("ReadBytes(5) = %q, want %q", got, "hello")
+	}
+
+	if _, err := child.ReadUint8(); err != io.EOF {
+		t.Fatalf("ReadUint8() past section boundary: err = %v, want io.EOF", err)
+	}
+}
+
+func TestSectionCloseDiscardsRemainder(t *testing.T) {
+	parent := NewReader(bytes.NewReader([]byte("hello world")))
+
+	child, err := parent.Section(5)
+	if err != nil {
+		t.Fatalf("Section: %v", err)
+	}
+
+	// Read only part of the section, then Close it without exhausting it.
+	if _, err := child.ReadBytes(2); err != nil {
+		t.Fatalf("ReadBytes(2) within section: %v", err)
+	}
+	if err := child.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// The parent should now be positioned right after the 5-byte section.
+	rest, err := parent.ReadBytes(6)
+	if err != nil {
+		t.Fatalf("ReadBytes(6) on parent after Close: %v", err)
+	}
+	if string(rest) != " world" {
+		t.Fatalf("parent read after Close = %q, want %q", rest, " world")
+	}
+}
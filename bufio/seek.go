@@ -0,0 +1,106 @@
+package bufio
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrNotSeekable is returned by the random-access Reader methods when the
+// underlying io.Reader does not implement io.Seeker.
+var ErrNotSeekable = errors.New("bufio: underlying reader is not seekable")
+
+// discardWindow drops the buffered window and any in-flight bit
+// accumulator state, since both are only valid for the read position they
+// were filled at. It also clears the sticky error, since a successful
+// seek moves the Reader onto data the sticky error no longer applies to.
+func (reader *Reader) discardWindow() {
+	reader.r, reader.w = 0, 0
+	reader.err = nil
+	reader.stickyErr = nil
+	reader.bitBuf, reader.bitCnt = 0, 0
+	reader.bitBufLE, reader.bitCntLE = 0, 0
+}
+
+// Pos returns the current absolute read position in the underlying
+// reader. It requires the underlying reader to implement io.Seeker; if it
+// does not, or the Seek call itself fails, Pos returns -1. Being a pure
+// query, it never touches the sticky error used by the fluent Read* API.
+func (reader *Reader) Pos() int64 {
+	seeker, ok := reader.rd.(io.Seeker)
+	if !ok {
+		return -1
+	}
+	cur, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return -1
+	}
+	return cur - int64(reader.Buffered())
+}
+
+// Len returns the total size of the underlying reader. It requires the
+// underlying reader to implement io.Seeker; if it does not, or any of the
+// Seek calls fail, Len returns -1. Being a pure query, it never touches
+// the sticky error used by the fluent Read* API.
+func (reader *Reader) Len() int64 {
+	seeker, ok := reader.rd.(io.Seeker)
+	if !ok {
+		return -1
+	}
+	cur, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return -1
+	}
+	end, err := seeker.Seek(0, io.SeekEnd)
+	if err != nil {
+		return -1
+	}
+	if _, err := seeker.Seek(cur, io.SeekStart); err != nil {
+		return -1
+	}
+	return end
+}
+
+// SeekAbs seeks to the absolute offset off in the underlying reader,
+// discarding any buffered bytes. It requires the underlying reader to
+// implement io.Seeker.
+func (reader *Reader) SeekAbs(off int64) error {
+	seeker, ok := reader.rd.(io.Seeker)
+	if !ok {
+		return ErrNotSeekable
+	}
+	if _, err := seeker.Seek(off, io.SeekStart); err != nil {
+		return err
+	}
+	reader.discardWindow()
+	return nil
+}
+
+// SeekRel seeks delta bytes relative to the current logical read
+// position (as reported by Pos), discarding any buffered bytes. It
+// requires the underlying reader to implement io.Seeker.
+func (reader *Reader) SeekRel(delta int64) error {
+	seeker, ok := reader.rd.(io.Seeker)
+	if !ok {
+		return ErrNotSeekable
+	}
+	cur, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	target := cur - int64(reader.Buffered()) + delta
+	if _, err := seeker.Seek(target, io.SeekStart); err != nil {
+		return err
+	}
+	reader.discardWindow()
+	return nil
+}
+
+// EOF reports whether the reader is exhausted: no bytes remain buffered
+// and the underlying reader has returned io.EOF.
+func (reader *Reader) EOF() bool {
+	if reader.Buffered() > 0 {
+		return false
+	}
+	_, err := reader.Peek(1)
+	return err == io.EOF
+}
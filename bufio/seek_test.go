@@ -0,0 +1,86 @@
+package bufio
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// nonSeekingReader wraps an io.Reader without exposing io.Seeker, even if
+// the underlying value would otherwise satisfy it.
+type nonSeekingReader struct {
+	r *strings.Reader
+}
+
+func (n *nonSeekingReader) Read(p []byte) (int, error) { return n.r.Read(p) }
+
+func TestPosLenNonSeekable(t *testing.T) {
+	reader := NewReader(&nonSeekingReader{r: strings.NewReader("hello")})
+
+	if got := reader.Pos(); got != -1 {
+		t.Fatalf("Pos() = %d, want -1", got)
+	}
+	if err := reader.Err(); err != nil {
+		t.Fatalf("Err() after Pos() = %v, want nil", err)
+	}
+
+	if got := reader.Len(); got != -1 {
+		t.Fatalf("Len() = %d, want -1", got)
+	}
+	if err := reader.Err(); err != nil {
+		t.Fatalf("Err() after Len() = %v, want nil", err)
+	}
+
+	// A plain query must not poison subsequent reads.
+	v, err := reader.ReadUint8()
+	if err != nil {
+		t.Fatalf("ReadUint8() after Pos/Len failed: %v", err)
+	}
+	if v != 'h' {
+		t.Fatalf("ReadUint8() = %q, want 'h'", v)
+	}
+}
+
+func TestPosLenSeekable(t *testing.T) {
+	reader := NewReader(bytes.NewReader([]byte{0x12, 0x34, 0xAB, 0xCD}))
+
+	if got, want := reader.Len(), int64(4); got != want {
+		t.Fatalf("Len() = %d, want %d", got, want)
+	}
+	if got, want := reader.Pos(), int64(0); got != want {
+		t.Fatalf("Pos() = %d, want %d", got, want)
+	}
+
+	if _, err := reader.ReadUint16BE(); err != nil {
+		t.Fatalf("ReadUint16BE() failed: %v", err)
+	}
+	if got, want := reader.Pos(), int64(2); got != want {
+		t.Fatalf("Pos() after read = %d, want %d", got, want)
+	}
+}
+
+func TestSeekAbsClearsStickyError(t *testing.T) {
+	reader := NewReader(bytes.NewReader([]byte{0x12, 0x34, 0xAB, 0xCD}))
+
+	if _, err := reader.Pop(10); err == nil {
+		t.Fatal("Pop(10) past EOF: err = nil, want io.EOF")
+	}
+	if reader.Err() == nil {
+		t.Fatal("Err() after Pop past EOF = nil, want io.EOF")
+	}
+
+	if err := reader.SeekAbs(0); err != nil {
+		t.Fatalf("SeekAbs(0): %v", err)
+	}
+	if err := reader.Err(); err != nil {
+		t.Fatalf("Err() after SeekAbs = %v, want nil", err)
+	}
+
+	v, err := reader.ReadUint8()
+	if err != nil {
+		t.Fatalf("ReadUint8() after SeekAbs: %v", err)
+	}
+	if v != 0x12 {
+		t.Fatalf("ReadUint8() = %#x, want 0x12", v)
+	}
+}
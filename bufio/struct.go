@@ -0,0 +1,465 @@
+package bufio
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ReadStruct and WriteStruct decode/encode a struct declaratively, in the
+// spirit of encoding/binary.Read/Write, but dispatching to the fast
+// buffered ReadUint16/32/64, ReadFloat*, etc. methods of Reader/Writer instead of
+// reading through reflect.Value byte by byte.
+//
+// Fields are read/written in declaration order using the given default
+// byte order, which a `binary:"be"` or `binary:"le"` struct tag can
+// override per field. Other supported tags:
+//
+//	binary:"skip=N"        - consume/emit N padding bytes; the field itself is untouched
+//	binary:"len=N"         - a []byte/[]T slice field has exactly N elements
+//	binary:"lenfrom=Field" - a []byte/[]T slice field's length is held in the
+//	                         previously decoded/set field named Field
+//
+// Nested structs and fixed-size arrays ([N]byte, [N]T) need no tag: their
+// length is already known from the Go type.
+
+type fieldTag struct {
+	order   binary.ByteOrder // nil: use the caller's default order
+	length  int              // -1: unspecified
+	lenFrom string           // "": unspecified
+	skip    int              // 0: unspecified
+}
+
+func parseFieldTag(raw string) (fieldTag, error) {
+	tag := fieldTag{length: -1}
+	if raw == "" {
+		return tag, nil
+	}
+	for _, part := range strings.Split(raw, ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "be":
+			tag.order = binary.BigEndian
+		case "le":
+			tag.order = binary.LittleEndian
+		case "len":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return tag, fmt.Errorf("bufio: bad len tag %q: %w", part, err)
+			}
+			tag.length = n
+		case "lenfrom":
+			tag.lenFrom = value
+		case "skip":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return tag, fmt.Errorf("bufio: bad skip tag %q: %w", part, err)
+			}
+			tag.skip = n
+		default:
+			return tag, fmt.Errorf("bufio: unknown struct tag %q", part)
+		}
+	}
+	return tag, nil
+}
+
+// intValue reads v, an integer of any signedness, as an int64.
+func intValue(v reflect.Value) (int64, error) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(v.Uint()), nil
+	default:
+		return 0, fmt.Errorf("bufio: lenfrom field has non-integer kind %s", v.Kind())
+	}
+}
+
+func structValue(v interface{}) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return reflect.Value{}, fmt.Errorf("bufio: ReadStruct/WriteStruct requires a non-nil pointer to a struct, got %T", v)
+	}
+	rv = rv.Elem()
+	if rv.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("bufio: ReadStruct/WriteStruct requires a pointer to a struct, got %T", v)
+	}
+	return rv, nil
+}
+
+// ReadStruct decodes v, a pointer to a struct, using order as the default
+// byte order for multi-byte fields.
+func (reader *Reader) ReadStruct(order binary.ByteOrder, v interface{}) error {
+	sv, err := structValue(v)
+	if err != nil {
+		return err
+	}
+	return reader.readStructValue(order, sv)
+}
+
+func (reader *Reader) readStructValue(order binary.ByteOrder, sv reflect.Value) error {
+	st := sv.Type()
+	for i := 0; i < st.NumField(); i++ {
+		field := st.Field(i)
+		tag, err := parseFieldTag(field.Tag.Get("binary"))
+		if err != nil {
+			return err
+		}
+		if tag.skip > 0 {
+			if _, err := reader.Discard(tag.skip); err != nil {
+				return fmt.Errorf("bufio: field %s: %w", field.Name, err)
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+		fieldOrder := order
+		if tag.order != nil {
+			fieldOrder = tag.order
+		}
+		if err := reader.readFieldValue(fieldOrder, sv.Field(i), sv, tag); err != nil {
+			return fmt.Errorf("bufio: field %s: %w", field.Name, err)
+		}
+	}
+	return nil
+}
+
+func (reader *Reader) readFieldValue(order binary.ByteOrder, fv, sv reflect.Value, tag fieldTag) error {
+	be := order == binary.BigEndian
+	switch fv.Kind() {
+	case reflect.Bool:
+		v, err := reader.ReadUint8()
+		if err != nil {
+			return err
+		}
+		fv.SetBool(v != 0)
+	case reflect.Int8:
+		v, err := reader.ReadInt8()
+		if err != nil {
+			return err
+		}
+		fv.SetInt(int64(v))
+	case reflect.Uint8:
+		v, err := reader.ReadUint8()
+		if err != nil {
+			return err
+		}
+		fv.SetUint(uint64(v))
+	case reflect.Int16:
+		var v int16
+		var err error
+		if be {
+			v, err = reader.ReadInt16BE()
+		} else {
+			v, err = reader.ReadInt16LE()
+		}
+		if err != nil {
+			return err
+		}
+		fv.SetInt(int64(v))
+	case reflect.Uint16:
+		var v uint16
+		var err error
+		if be {
+			v, err = reader.ReadUint16BE()
+		} else {
+			v, err = reader.ReadUint16LE()
+		}
+		if err != nil {
+			return err
+		}
+		fv.SetUint(uint64(v))
+	case reflect.Int32:
+		var v int32
+		var err error
+		if be {
+			v, err = reader.ReadInt32BE()
+		} else {
+			v, err = reader.ReadInt32LE()
+		}
+		if err != nil {
+			return err
+		}
+		fv.SetInt(int64(v))
+	case reflect.Uint32:
+		var v uint32
+		var err error
+		if be {
+			v, err = reader.ReadUint32BE()
+		} else {
+			v, err = reader.ReadUint32LE()
+		}
+		if err != nil {
+			return err
+		}
+		fv.SetUint(uint64(v))
+	case reflect.Int64:
+		var v int64
+		var err error
+		if be {
+			v, err = reader.ReadInt64BE()
+		} else {
+			v, err = reader.ReadInt64LE()
+		}
+		if err != nil {
+			return err
+		}
+		fv.SetInt(v)
+	case reflect.Uint64:
+		var v uint64
+		var err error
+		if be {
+			v, err = reader.ReadUint64BE()
+		} else {
+			v, err = reader.ReadUint64LE()
+		}
+		if err != nil {
+			return err
+		}
+		fv.SetUint(v)
+	case reflect.Float32:
+		var v float32
+		var err error
+		if be {
+			v, err = reader.ReadFloat32BE()
+		} else {
+			v, err = reader.ReadFloat32LE()
+		}
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(float64(v))
+	case reflect.Float64:
+		var v float64
+		var err error
+		if be {
+			v, err = reader.ReadFloat64BE()
+		} else {
+			v, err = reader.ReadFloat64LE()
+		}
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(v)
+	case reflect.Struct:
+		return reader.readStructValue(order, fv)
+	case reflect.Array:
+		return reader.readArray(order, fv)
+	case reflect.Slice:
+		return reader.readSlice(order, fv, sv, tag)
+	default:
+		return fmt.Errorf("bufio: unsupported field kind %s", fv.Kind())
+	}
+	return nil
+}
+
+func (reader *Reader) readArray(order binary.ByteOrder, fv reflect.Value) error {
+	n := fv.Len()
+	if fv.Type().Elem().Kind() == reflect.Uint8 {
+		b, err := reader.ReadBytes(n)
+		if err != nil {
+			return err
+		}
+		reflect.Copy(fv, reflect.ValueOf(b))
+		return nil
+	}
+	for i := 0; i < n; i++ {
+		if err := reader.readFieldValue(order, fv.Index(i), fv, fieldTag{length: -1}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (reader *Reader) readSlice(order binary.ByteOrder, fv, sv reflect.Value, tag fieldTag) error {
+	n, err := sliceLen(sv, tag)
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return ErrNegativeCount
+	}
+	// A lenfrom field is attacker-controlled wire data, just like a
+	// ReadBytesU* length prefix, so it's held to the same bound.
+	if err := reader.checkFramedLen(n); err != nil {
+		return err
+	}
+	if fv.Type().Elem().Kind() == reflect.Uint8 {
+		b, err := reader.ReadBytes(n)
+		if err != nil {
+			return err
+		}
+		fv.SetBytes(b)
+		return nil
+	}
+	sl := reflect.MakeSlice(fv.Type(), n, n)
+	for i := 0; i < n; i++ {
+		if err := reader.readFieldValue(order, sl.Index(i), sv, fieldTag{length: -1}); err != nil {
+			return err
+		}
+	}
+	fv.Set(sl)
+	return nil
+}
+
+func sliceLen(sv reflect.Value, tag fieldTag) (int, error) {
+	switch {
+	case tag.length >= 0:
+		return tag.length, nil
+	case tag.lenFrom != "":
+		lf := sv.FieldByName(tag.lenFrom)
+		if !lf.IsValid() {
+			return 0, fmt.Errorf("bufio: lenfrom field %q not found", tag.lenFrom)
+		}
+		n, err := intValue(lf)
+		if err != nil {
+			return 0, err
+		}
+		return int(n), nil
+	default:
+		return 0, fmt.Errorf("bufio: slice field needs a `len=` or `lenfrom=` tag")
+	}
+}
+
+// WriteStruct encodes v, a pointer to a struct, using order as the
+// default byte order for multi-byte fields.
+func (writer *Writer) WriteStruct(order binary.ByteOrder, v interface{}) error {
+	sv, err := structValue(v)
+	if err != nil {
+		return err
+	}
+	return writer.writeStructValue(order, sv)
+}
+
+func (writer *Writer) writeStructValue(order binary.ByteOrder, sv reflect.Value) error {
+	st := sv.Type()
+	for i := 0; i < st.NumField(); i++ {
+		field := st.Field(i)
+		tag, err := parseFieldTag(field.Tag.Get("binary"))
+		if err != nil {
+			return err
+		}
+		if tag.skip > 0 {
+			if _, err := writer.WriteBytes(make([]byte, tag.skip)); err != nil {
+				return fmt.Errorf("bufio: field %s: %w", field.Name, err)
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+		fieldOrder := order
+		if tag.order != nil {
+			fieldOrder = tag.order
+		}
+		if err := writer.writeFieldValue(fieldOrder, sv.Field(i)); err != nil {
+			return fmt.Errorf("bufio: field %s: %w", field.Name, err)
+		}
+	}
+	return nil
+}
+
+func (writer *Writer) writeFieldValue(order binary.ByteOrder, fv reflect.Value) error {
+	be := order == binary.BigEndian
+	var err error
+	switch fv.Kind() {
+	case reflect.Bool:
+		v := uint8(0)
+		if fv.Bool() {
+			v = 1
+		}
+		_, err = writer.WriteUint8(v)
+	case reflect.Int8:
+		_, err = writer.WriteInt8(int8(fv.Int()))
+	case reflect.Uint8:
+		_, err = writer.WriteUint8(uint8(fv.Uint()))
+	case reflect.Int16:
+		if be {
+			_, err = writer.WriteInt16BE(int16(fv.Int()))
+		} else {
+			_, err = writer.WriteInt16LE(int16(fv.Int()))
+		}
+	case reflect.Uint16:
+		if be {
+			_, err = writer.WriteUint16BE(uint16(fv.Uint()))
+		} else {
+			_, err = writer.WriteUint16LE(uint16(fv.Uint()))
+		}
+	case reflect.Int32:
+		if be {
+			_, err = writer.WriteInt32BE(int32(fv.Int()))
+		} else {
+			_, err = writer.WriteInt32LE(int32(fv.Int()))
+		}
+	case reflect.Uint32:
+		if be {
+			_, err = writer.WriteUint32BE(uint32(fv.Uint()))
+		} else {
+			_, err = writer.WriteUint32LE(uint32(fv.Uint()))
+		}
+	case reflect.Int64:
+		if be {
+			_, err = writer.WriteInt64BE(fv.Int())
+		} else {
+			_, err = writer.WriteInt64LE(fv.Int())
+		}
+	case reflect.Uint64:
+		if be {
+			_, err = writer.WriteUint64BE(fv.Uint())
+		} else {
+			_, err = writer.WriteUint64LE(fv.Uint())
+		}
+	case reflect.Float32:
+		if be {
+			_, err = writer.WriteFloat32BE(float32(fv.Float()))
+		} else {
+			_, err = writer.WriteFloat32LE(float32(fv.Float()))
+		}
+	case reflect.Float64:
+		if be {
+			_, err = writer.WriteFloat64BE(fv.Float())
+		} else {
+			_, err = writer.WriteFloat64LE(fv.Float())
+		}
+	case reflect.Struct:
+		return writer.writeStructValue(order, fv)
+	case reflect.Array:
+		return writer.writeArray(order, fv)
+	case reflect.Slice:
+		return writer.writeSlice(order, fv)
+	default:
+		return fmt.Errorf("bufio: unsupported field kind %s", fv.Kind())
+	}
+	return err
+}
+
+func (writer *Writer) writeArray(order binary.ByteOrder, fv reflect.Value) error {
+	if fv.Type().Elem().Kind() == reflect.Uint8 {
+		b := make([]byte, fv.Len())
+		reflect.Copy(reflect.ValueOf(b), fv)
+		_, err := writer.WriteBytes(b)
+		return err
+	}
+	for i := 0; i < fv.Len(); i++ {
+		if err := writer.writeFieldValue(order, fv.Index(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (writer *Writer) writeSlice(order binary.ByteOrder, fv reflect.Value) error {
+	if fv.Type().Elem().Kind() == reflect.Uint8 {
+		_, err := writer.WriteBytes(fv.Bytes())
+		return err
+	}
+	for i := 0; i < fv.Len(); i++ {
+		if err := writer.writeFieldValue(order, fv.Index(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
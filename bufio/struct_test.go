@@ -0,0 +1,84 @@
+package bufio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+func TestReadStructLenFromRejectsNegativeLength(t *testing.T) {
+	type frame struct {
+		Count int32  `binary:"be"`
+		Data  []byte `binary:"lenfrom=Count"`
+	}
+	var buf bytes.Buffer
+	buf.Write([]byte{0xFF, 0xFF, 0xFF, 0xFF}) // Count == -1
+
+	var f frame
+	err := NewReader(&buf).ReadStruct(binary.BigEndian, &f)
+	if err == nil {
+		t.Fatal("ReadStruct() err = nil, want an error for a negative lenfrom length")
+	}
+}
+
+func TestReadStructLenFromRejectsOversizedLength(t *testing.T) {
+	type frame struct {
+		Count int32  `binary:"be"`
+		Data  []byte `binary:"lenfrom=Count"`
+	}
+	var buf bytes.Buffer
+	buf.Write([]byte{0x7F, 0xFF, 0xFF, 0xFF}) // Count == 2^31-1, far past the default max
+
+	var f frame
+	err := NewReader(&buf).ReadStruct(binary.BigEndian, &f)
+	if !errors.Is(err, ErrFrameTooLarge) {
+		t.Fatalf("ReadStruct() err = %v, want ErrFrameTooLarge", err)
+	}
+}
+
+func TestReadWriteStructRoundTrip(t *testing.T) {
+	type inner struct {
+		X uint16 `binary:"be"`
+		Y uint16 `binary:"be"`
+	}
+	type outer struct {
+		Magic   [4]byte
+		Count   uint8
+		Payload []byte   `binary:"lenfrom=Count"`
+		Fixed   []uint16 `binary:"len=2,be"`
+		Point   inner
+		_       uint8  `binary:"skip=1"`
+		Tail    uint32 `binary:"le"`
+	}
+
+	want := outer{
+		Magic:   [4]byte{'B', 'I', 'N', '1'},
+		Count:   3,
+		Payload: []byte{1, 2, 3},
+		Fixed:   []uint16{10, 20},
+		Point:   inner{X: 7, Y: 9},
+		Tail:    0xdeadbeef,
+	}
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	if err := w.WriteStruct(binary.BigEndian, &want); err != nil {
+		t.Fatalf("WriteStruct: %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	var got outer
+	if err := NewReader(&buf).ReadStruct(binary.BigEndian, &got); err != nil {
+		t.Fatalf("ReadStruct: %v", err)
+	}
+
+	if got.Magic != want.Magic || got.Count != want.Count ||
+		!bytes.Equal(got.Payload, want.Payload) ||
+		len(got.Fixed) != len(want.Fixed) || got.Fixed[0] != want.Fixed[0] || got.Fixed[1] != want.Fixed[1] ||
+		got.Point != want.Point || got.Tail != want.Tail {
+		t.Fatalf("round trip mismatch: got %+v, want %+v", got, want)
+	}
+}
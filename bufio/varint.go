@@ -0,0 +1,80 @@
+package bufio
+
+import "errors"
+
+// ErrOverflow is returned by ReadUvarint/ReadVarint when the varint does
+// not terminate within 10 bytes, or the 10th byte has bits set beyond the
+// single bit needed to represent the top bit of a 64-bit value. This
+// mirrors encoding/binary's ErrOverflow.
+var ErrOverflow = errors.New("bufio: varint overflows a 64-bit integer")
+
+// maxVarintLen64 is the maximum number of bytes a varint-encoded uint64 can
+// occupy under the 7-bits-per-byte wire format.
+const maxVarintLen64 = 10
+
+// ReadUvarint reads a base-128, little-endian, variable-length encoded
+// uint64 following the same wire format as encoding/binary.Uvarint: each
+// byte contributes its low 7 bits, most-significant-byte-of-the-group
+// last, with bit 7 set to indicate that more bytes follow.
+func (reader *Reader) ReadUvarint() (uint64, error) {
+	var v uint64
+	var shift uint
+	for i := 0; i < maxVarintLen64; i++ {
+		// Read through Pop, not ReadByte: ReadByte bypasses the internal
+		// buffer whenever the underlying io.Reader also implements
+		// io.ByteReader, which would desync this loop from bytes already
+		// sitting in buf after a prior fixed-width Read*.
+		p, err := reader.Pop(1)
+		if err != nil {
+			return 0, err
+		}
+		b := p[0]
+		if i == maxVarintLen64-1 && b > 1 {
+			return 0, ErrOverflow
+		}
+		if b < 0x80 {
+			return v | uint64(b)<<shift, nil
+		}
+		v |= uint64(b&0x7f) << shift
+		shift += 7
+	}
+	return 0, ErrOverflow
+}
+
+// ReadVarint reads a zigzag-encoded, variable-length int64, as written by
+// WriteVarint.
+func (reader *Reader) ReadVarint() (int64, error) {
+	uv, err := reader.ReadUvarint()
+	if err != nil {
+		return 0, err
+	}
+	v := int64(uv >> 1)
+	if uv&1 != 0 {
+		v = ^v
+	}
+	return v, nil
+}
+
+// WriteUvarint writes v as a base-128, little-endian, variable-length
+// encoded uint64, following the same wire format as
+// encoding/binary.PutUvarint.
+func (writer *Writer) WriteUvarint(v uint64) (int, error) {
+	var buf [maxVarintLen64]byte
+	n := 0
+	for v >= 0x80 {
+		buf[n] = byte(v) | 0x80
+		v >>= 7
+		n++
+	}
+	buf[n] = byte(v)
+	n++
+	return writer.WriteBytes(buf[:n])
+}
+
+// WriteVarint writes v as a zigzag-encoded, variable-length int64:
+// (v << 1) ^ (v >> 63), which maps small-magnitude signed values onto
+// small unsigned ones so the varint encoding stays compact.
+func (writer *Writer) WriteVarint(v int64) (int, error) {
+	uv := (uint64(v) << 1) ^ uint64(v>>63)
+	return writer.WriteUvarint(uv)
+}
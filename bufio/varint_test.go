@@ -0,0 +1,64 @@
+package bufio
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestUvarintRoundTrip(t *testing.T) {
+	values := []uint64{0, 1, 127, 128, 300, 1 << 32, ^uint64(0)}
+	for _, v := range values {
+		var buf bytes.Buffer
+		w := NewWriter(&buf)
+		if _, err := w.WriteUvarint(v); err != nil {
+			t.Fatalf("WriteUvarint(%d): %v", v, err)
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush: %v", err)
+		}
+
+		r := NewReader(&buf)
+		got, err := r.ReadUvarint()
+		if err != nil {
+			t.Fatalf("ReadUvarint() for %d: %v", v, err)
+		}
+		if got != v {
+			t.Fatalf("ReadUvarint() = %d, want %d", got, v)
+		}
+	}
+}
+
+func TestUvarintOverflow(t *testing.T) {
+	// 10 bytes, continuation bit set on all but the last, and the last
+	// byte has more than the single bit allowed for a 64-bit value.
+	b := append(bytes.Repeat([]byte{0xff}, maxVarintLen64-1), 0x02)
+	r := NewReader(bytes.NewReader(b))
+	if _, err := r.ReadUvarint(); err != ErrOverflow {
+		t.Fatalf("ReadUvarint() err = %v, want ErrOverflow", err)
+	}
+}
+
+// TestUvarintAfterFixedWidthRead is a regression test for ReadByte
+// bypassing the internal buffer on a reader that also implements
+// io.ByteReader (e.g. *strings.Reader): a prior ReadUint16BE must not
+// cause the following ReadUvarint to read past the buffered data.
+func TestUvarintAfterFixedWidthRead(t *testing.T) {
+	r := NewReader(strings.NewReader("\x12\x34\xAC\x02"))
+
+	v16, err := r.ReadUint16BE()
+	if err != nil {
+		t.Fatalf("ReadUint16BE: %v", err)
+	}
+	if v16 != 0x1234 {
+		t.Fatalf("ReadUint16BE() = %#x, want 0x1234", v16)
+	}
+
+	v, err := r.ReadUvarint()
+	if err != nil {
+		t.Fatalf("ReadUvarint: %v", err)
+	}
+	if want := uint64(300); v != want {
+		t.Fatalf("ReadUvarint() = %d, want %d", v, want)
+	}
+}